@@ -0,0 +1,123 @@
+package datastore
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// IndexSpec builds the Milvus index parameters for a single vector field,
+// so CreateCollection can pick the index that best matches a collection's
+// size and latency requirements instead of a single hardcoded IVF_FLAT.
+type IndexSpec interface {
+	// FieldName is the vector field the index is built on, e.g. "embedding"
+	// or "sparse_embedding".
+	FieldName() string
+	// BuildIndex returns the entity.Index Client.CreateIndex expects.
+	BuildIndex() (entity.Index, error)
+}
+
+// IVFFlatIndexSpec builds an IVF_FLAT index, Milvus' exact-recall baseline,
+// partitioning vectors into Nlist coarse clusters.
+type IVFFlatIndexSpec struct {
+	Field  string
+	Metric entity.MetricType
+	Nlist  int
+}
+
+func (s IVFFlatIndexSpec) FieldName() string { return s.Field }
+
+func (s IVFFlatIndexSpec) BuildIndex() (entity.Index, error) {
+	idx, err := entity.NewIndexIvfFlat(s.Metric, s.Nlist)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create IVF_FLAT index parameter: %w", err)
+	}
+
+	return idx, nil
+}
+
+// IVFSQ8IndexSpec builds an IVF_SQ8 index, trading a small amount of recall
+// for roughly a 4x reduction in index size via scalar quantization.
+type IVFSQ8IndexSpec struct {
+	Field  string
+	Metric entity.MetricType
+	Nlist  int
+}
+
+func (s IVFSQ8IndexSpec) FieldName() string { return s.Field }
+
+func (s IVFSQ8IndexSpec) BuildIndex() (entity.Index, error) {
+	idx, err := entity.NewIndexIvfSQ8(s.Metric, s.Nlist)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create IVF_SQ8 index parameter: %w", err)
+	}
+
+	return idx, nil
+}
+
+// HNSWIndexSpec builds an HNSW graph index for latency-critical search
+// paths, trading index build time and memory for fast approximate search.
+type HNSWIndexSpec struct {
+	Field          string
+	Metric         entity.MetricType
+	M              int
+	EfConstruction int
+}
+
+func (s HNSWIndexSpec) FieldName() string { return s.Field }
+
+func (s HNSWIndexSpec) BuildIndex() (entity.Index, error) {
+	idx, err := entity.NewIndexHNSW(s.Metric, s.M, s.EfConstruction)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create HNSW index parameter: %w", err)
+	}
+
+	return idx, nil
+}
+
+// DiskANNIndexSpec builds a DISKANN index, which serves large collections
+// straight from disk instead of requiring the whole index to be memory-resident.
+type DiskANNIndexSpec struct {
+	Field  string
+	Metric entity.MetricType
+}
+
+func (s DiskANNIndexSpec) FieldName() string { return s.Field }
+
+func (s DiskANNIndexSpec) BuildIndex() (entity.Index, error) {
+	idx, err := entity.NewIndexDISKANN(s.Metric)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create DISKANN index parameter: %w", err)
+	}
+
+	return idx, nil
+}
+
+// SparseInvertedIndexSpec builds a SPARSE_INVERTED_INDEX over a sparse
+// float vector field, e.g. the BM25/SPLADE sparse embeddings written by
+// UpsertDocuments and searched by HybridSearch.
+type SparseInvertedIndexSpec struct {
+	Field     string
+	Metric    entity.MetricType
+	DropRatio float64
+}
+
+func (s SparseInvertedIndexSpec) FieldName() string { return s.Field }
+
+func (s SparseInvertedIndexSpec) BuildIndex() (entity.Index, error) {
+	idx, err := entity.NewIndexSparseInverted(s.Metric, s.DropRatio)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create SPARSE_INVERTED_INDEX index parameter: %w", err)
+	}
+
+	return idx, nil
+}
+
+// DefaultIndexSpecs returns the dense IVF_FLAT + sparse SPARSE_INVERTED_INDEX
+// pair CreateCollection built before index selection became pluggable.
+func DefaultIndexSpecs() []IndexSpec {
+	return []IndexSpec{
+		IVFFlatIndexSpec{Field: "embedding", Metric: entity.COSINE, Nlist: 1024},
+		SparseInvertedIndexSpec{Field: "sparse_embedding", Metric: entity.IP, DropRatio: 0.2},
+	}
+}
@@ -0,0 +1,75 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+)
+
+// Warmup loads collectionName into query node memory ahead of time, so the
+// first Search/HybridSearch against it doesn't pay load latency inline.
+// Subsequent Search/HybridSearch calls reuse the cached load state instead
+// of reloading.
+func (m *DatastoreMilvusRepository) Warmup(collectionName string) error {
+	return m.ensureLoaded(collectionName)
+}
+
+// ensureLoaded loads collectionName unless the load-state cache already marks
+// it loaded, avoiding the load/release thrash of calling LoadCollection on
+// every query.
+func (m *DatastoreMilvusRepository) ensureLoaded(collectionName string) error {
+	if m.loadState.isLoaded(collectionName) {
+		return nil
+	}
+
+	if err := m.Client.LoadCollection(context.Background(), collectionName, false); err != nil {
+		return fmt.Errorf("failed to load collection: %w", err)
+	}
+
+	m.loadState.markLoaded(collectionName)
+
+	return nil
+}
+
+// CreatePartition creates partitionName under collectionName, letting
+// tenants or time-windowed corpora be isolated within a single collection.
+// It invalidates collectionName's load-state cache entry, since
+// LoadCollection only loads the partitions that existed at load time — the
+// next Search/HybridSearch against collectionName reloads it so the new
+// partition is actually queryable instead of silently returning no results.
+func (m *DatastoreMilvusRepository) CreatePartition(collectionName string, partitionName string) error {
+	if err := m.Client.CreatePartition(context.Background(), collectionName, partitionName); err != nil {
+		return fmt.Errorf("failed to create partition: %w", err)
+	}
+
+	m.loadState.markReleased(collectionName)
+
+	return nil
+}
+
+// DropPartition drops partitionName from collectionName.
+func (m *DatastoreMilvusRepository) DropPartition(collectionName string, partitionName string) error {
+	if err := m.Client.DropPartition(context.Background(), collectionName, partitionName); err != nil {
+		return fmt.Errorf("failed to drop partition: %w", err)
+	}
+
+	return nil
+}
+
+// CreateAlias points a new alias at collectionName.
+func (m *DatastoreMilvusRepository) CreateAlias(collectionName string, alias string) error {
+	if err := m.Client.CreateAlias(context.Background(), collectionName, alias); err != nil {
+		return fmt.Errorf("failed to create alias: %w", err)
+	}
+
+	return nil
+}
+
+// AlterAlias repoints an existing alias at collectionName, so blue/green
+// reindexing can flip readers atomically without downtime.
+func (m *DatastoreMilvusRepository) AlterAlias(collectionName string, alias string) error {
+	if err := m.Client.AlterAlias(context.Background(), collectionName, alias); err != nil {
+		return fmt.Errorf("failed to alter alias: %w", err)
+	}
+
+	return nil
+}
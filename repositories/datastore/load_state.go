@@ -0,0 +1,36 @@
+package datastore
+
+import "sync"
+
+// loadStateCache tracks which collections are currently loaded into query
+// node memory, so Search/HybridSearch only call LoadCollection on first use
+// instead of loading and releasing around every query.
+type loadStateCache struct {
+	mu     sync.RWMutex
+	loaded map[string]bool
+}
+
+func newLoadStateCache() *loadStateCache {
+	return &loadStateCache{loaded: make(map[string]bool)}
+}
+
+func (c *loadStateCache) isLoaded(collectionName string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.loaded[collectionName]
+}
+
+func (c *loadStateCache) markLoaded(collectionName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loaded[collectionName] = true
+}
+
+func (c *loadStateCache) markReleased(collectionName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.loaded, collectionName)
+}
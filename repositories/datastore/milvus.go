@@ -6,29 +6,49 @@ import (
 	"github.com/pablosanchi/datastore/core/ports/secondary"
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 	"context"
 )
 
+// rrfK is the rank-smoothing constant used by reciprocal rank fusion when
+// merging the dense and sparse sub-searches of HybridSearch.
+const rrfK = 60
+
 type DatastoreMilvusRepository struct{
 	Client client.Client
 	Encoder secondary.TextEncoder
+	SparseEncoder secondary.SparseEncoder
+	loadState *loadStateCache
 }
 
-func NewDatastoreMilvusRepository(milvusClient client.Client, encoder secondary.TextEncoder) ports.DatastoreRepository {
+func NewDatastoreMilvusRepository(milvusClient client.Client, encoder secondary.TextEncoder, sparseEncoder secondary.SparseEncoder) ports.DatastoreRepository {
 	return &DatastoreMilvusRepository{
-		Client: milvusClient, 
+		Client: milvusClient,
 		Encoder: encoder,
+		SparseEncoder: sparseEncoder,
+		loadState: newLoadStateCache(),
 	}
 }
 
-func (m *DatastoreMilvusRepository) CreateCollection(collectionName string) error {
+// CreateCollection creates collectionName and builds one index per
+// indexSpec. Callers that don't care about index selection can pass
+// DefaultIndexSpecs(), which reproduces the previous hardcoded IVF_FLAT /
+// SPARSE_INVERTED_INDEX pair.
+func (m *DatastoreMilvusRepository) CreateCollection(collectionName string, indexSpecs ...IndexSpec) error {
     schema := defineSchema(collectionName)
     if err := m.Client.CreateCollection(context.Background(), schema, entity.DefaultShardNumber); err != nil {
         return fmt.Errorf("failed to create collection: %w", err)
     }
 
-    if err := m.buildIndex(collectionName); err != nil {
+    if len(indexSpecs) == 0 {
+        indexSpecs = DefaultIndexSpecs()
+    }
+
+    if err := m.buildIndexes(collectionName, indexSpecs); err != nil {
         return fmt.Errorf("failed to build index: %w", err)
     }
 
@@ -40,6 +60,8 @@ func (m *DatastoreMilvusRepository) DeleteCollection(collectionName string) erro
         return fmt.Errorf("failed to drop collection: %w", err)
     }
 
+    m.loadState.markReleased(collectionName)
+
     return nil
 }
 
@@ -57,13 +79,137 @@ func (m *DatastoreMilvusRepository) List() ([]string, error) {
     return collections, nil
 }
 
-func (m *DatastoreMilvusRepository) UpsertDocuments(collectionName string, documents []domain.Document) error {
+// UpsertDocuments writes documents to the first partition in partitionNames
+// (pass nil for the default partition) using DefaultUpsertOptions. Use
+// UpsertDocumentsWithOptions or UpsertDocumentsStream to tune batch size,
+// concurrency or retry behaviour for large corpora.
+func (m *DatastoreMilvusRepository) UpsertDocuments(collectionName string, documents []domain.Document, partitionNames []string) error {
+	return m.UpsertDocumentsWithOptions(context.Background(), collectionName, documents, partitionNames, DefaultUpsertOptions())
+}
+
+// UpsertDocumentsWithOptions chunks documents into opts.BatchSize batches
+// and upserts them through UpsertDocumentsStream, so callers that already
+// hold the full corpus in memory still benefit from bounded, concurrent,
+// retrying batch flushes instead of one unbounded encode-then-upsert call.
+func (m *DatastoreMilvusRepository) UpsertDocumentsWithOptions(ctx context.Context, collectionName string, documents []domain.Document, partitionNames []string, opts UpsertOptions) error {
+	docs := make(chan domain.Document)
+
+	go func() {
+		defer close(docs)
+		for _, document := range documents {
+			docs <- document
+		}
+	}()
+
+	return m.UpsertDocumentsStream(ctx, collectionName, docs, partitionNames, opts)
+}
+
+// UpsertDocumentsStream consumes documents from docs as they arrive,
+// encoding and flushing them in batches of opts.BatchSize across
+// opts.Concurrency worker goroutines so later batches keep encoding while
+// earlier ones are still being upserted. It lets callers pipe ingestion
+// directly from a scraper or a Kafka consumer without materializing the
+// whole corpus in memory.
+func (m *DatastoreMilvusRepository) UpsertDocumentsStream(ctx context.Context, collectionName string, docs <-chan domain.Document, partitionNames []string, opts UpsertOptions) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultUpsertOptions().BatchSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultUpsertOptions().Concurrency
+	}
+	if opts.RetryPolicy.MaxAttempts <= 0 {
+		opts.RetryPolicy = DefaultRetryPolicy()
+	}
+
+	batches := make(chan []domain.Document, opts.Concurrency)
+
+	go func() {
+		defer close(batches)
+		batch := make([]domain.Document, 0, opts.BatchSize)
+		for document := range docs {
+			batch = append(batch, document)
+			if len(batch) == opts.BatchSize {
+				batches <- batch
+				batch = make([]domain.Document, 0, opts.BatchSize)
+			}
+		}
+		if len(batch) > 0 {
+			batches <- batch
+		}
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		upserted int
+		firstErr error
+	)
+
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				err := m.upsertBatchWithRetry(ctx, collectionName, batch, partitionNames, opts.RetryPolicy)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					upserted += len(batch)
+					if opts.OnProgress != nil {
+						opts.OnProgress(upserted)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// upsertBatchWithRetry flushes a single batch, retrying with exponential
+// backoff while the error looks like a transient gRPC failure.
+func (m *DatastoreMilvusRepository) upsertBatchWithRetry(ctx context.Context, collectionName string, batch []domain.Document, partitionNames []string, policy RetryPolicy) error {
+	var err error
+
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		if err = m.upsertBatch(ctx, collectionName, batch, partitionNames); err == nil {
+			return nil
+		}
+
+		if attempt == policy.MaxAttempts-1 || !isTransient(err) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(policy.delay(attempt)):
+		}
+	}
+
+	return fmt.Errorf("fail to upsert batch after retries: %w", err)
+}
+
+// upsertBatch encodes one batch of documents (dense embedding, sparse
+// embedding, dynamic JSON metadata) and issues a single Upsert call against
+// the first name in partitionNames, or the default partition if empty —
+// Milvus' Upsert only ever targets one partition per call.
+func (m *DatastoreMilvusRepository) upsertBatch(ctx context.Context, collectionName string, documents []domain.Document, partitionNames []string) error {
 	nEntities := len(documents)
 	idList:= make([]string, 0, nEntities)
 	titleList:= make([]string, 0, nEntities)
 	contentList:= make([]string, 0, nEntities)
 	categoryList:= make([]string, 0, nEntities)
 	embeddingList := make([][]float32, 0, nEntities)
+	sparseEmbeddingList := make([]entity.SparseEmbedding, 0, nEntities)
+	metadataList := make([][]byte, 0, nEntities)
 
 	for _, document := range documents {
 		encodedContent, err := m.Encoder.Encode(document.Content)
@@ -71,12 +217,26 @@ func (m *DatastoreMilvusRepository) UpsertDocuments(collectionName string, docum
 		if err != nil {
 			return fmt.Errorf("fail to encode content: %w", err)
 		}
-		
+
+		encodedSparseContent, err := m.SparseEncoder.Encode(document.Content)
+
+		if err != nil {
+			return fmt.Errorf("fail to sparse encode content: %w", err)
+		}
+
+		encodedMetadata, err := json.Marshal(document.Metadata)
+
+		if err != nil {
+			return fmt.Errorf("fail to marshal metadata: %w", err)
+		}
+
 		idList = append(idList, document.ID)
 		titleList = append(titleList, document.Title)
 		contentList = append(contentList, document.Content)
 		categoryList = append(categoryList, document.Category)
 		embeddingList = append(embeddingList, encodedContent)
+		sparseEmbeddingList = append(sparseEmbeddingList, encodedSparseContent)
+		metadataList = append(metadataList, encodedMetadata)
 	}
 
 	idColumn := entity.NewColumnVarChar("id", idList)
@@ -84,16 +244,25 @@ func (m *DatastoreMilvusRepository) UpsertDocuments(collectionName string, docum
 	contentColumn := entity.NewColumnVarChar("content", contentList)
 	categoryColumn := entity.NewColumnVarChar("category", categoryList)
 	embeddingColumn := entity.NewColumnFloatVector("embedding", 4096, embeddingList)
+	sparseEmbeddingColumn := entity.NewColumnSparseVectors("sparse_embedding", sparseEmbeddingList)
+	metadataColumn := entity.NewColumnJSONBytes("$meta", metadataList)
+
+	var partitionName string
+	if len(partitionNames) > 0 {
+		partitionName = partitionNames[0]
+	}
 
 	if _, err := m.Client.Upsert(
-		context.Background(), 
-		collectionName, 
-		"",
+		ctx,
+		collectionName,
+		partitionName,
 		idColumn,
 		titleColumn,
 		contentColumn,
 		categoryColumn,
-		embeddingColumn,	
+		embeddingColumn,
+		sparseEmbeddingColumn,
+		metadataColumn,
 	);
 
 	err != nil {
@@ -103,7 +272,19 @@ func (m *DatastoreMilvusRepository) UpsertDocuments(collectionName string, docum
 	return nil
 }
 
-func (m *DatastoreMilvusRepository) Search(collectionName string, query string) ([]domain.Document, error) {
+// Search runs a dense ANN search for query over partitionNames (pass nil to
+// search the whole collection), optionally narrowed by filter, a Milvus
+// boolean expression (e.g. `category in ["a","b"] and year > 2020`)
+// evaluated against scalar and dynamic metadata fields. Pass an empty
+// string for no filtering. opts.Params must match the IndexSpec the
+// collection's "embedding" field was built with (e.g. HNSWSearchParams for
+// an HNSWIndexSpec); pass DefaultSearchOptions() to reproduce the previous
+// hardcoded IVF_FLAT/top-3/strong-consistency behaviour. The collection is
+// loaded on first use and then reused from the load-state cache (see
+// Warmup) instead of being loaded and released around every call;
+// CreatePartition and DeleteCollection clear its cache entry, so the next
+// call loads it again.
+func (m *DatastoreMilvusRepository) Search(collectionName string, query string, filter string, partitionNames []string, opts SearchOptions) ([]domain.Document, error) {
 
 	encodedQuery, err := m.Encoder.Encode(query)
 
@@ -111,31 +292,29 @@ func (m *DatastoreMilvusRepository) Search(collectionName string, query string)
 		return nil, fmt.Errorf("fail to encode query: %w", err)
 	}
 
-	if err := m.Client.LoadCollection(context.Background(), collectionName, false, ); err != nil {
-		return nil, fmt.Errorf("failed to load collection: %w", err)
+	if err := m.ensureLoaded(collectionName); err != nil {
+		return nil, err
 	}
 
-	sp, _ := entity.NewIndexIvfFlatSearchParam(10,)
-	
-	opt := client.SearchQueryOptionFunc(func(option *client.SearchQueryOption) {
-		option.Limit = 3
-		option.Offset = 0
-		option.ConsistencyLevel = entity.ClStrong
-		option.IgnoreGrowing = false
-	})
+	opts = opts.withDefaults()
+
+	sp, err := opts.Params.BuildSearchParams()
+	if err != nil {
+		return nil, fmt.Errorf("fail to build search params: %w", err)
+	}
 
 	searchResult, err := m.Client.Search(
 		context.Background(),
 		collectionName,
-		[]string{},
-		"",
-		[]string{"title", "content", "category"},
+		partitionNames,
+		filter,
+		[]string{"id", "title", "content", "category", "$meta"},
 		[]entity.Vector{entity.FloatVector(encodedQuery)},
 		"embedding",
 		entity.COSINE,
-		10,
+		opts.TopK,
 		sp,
-		opt,
+		opts.searchQueryOption(),
 	)
 
 	if err != nil {
@@ -143,46 +322,268 @@ func (m *DatastoreMilvusRepository) Search(collectionName string, query string)
 	}
 
 	fields := searchResult[0].Fields
+	idList := fields.GetColumn("id")
 	titleList := fields.GetColumn("title")
 	contentList := fields.GetColumn("content")
 	categoryList := fields.GetColumn("category")
+	metaList := fields.GetColumn("$meta")
 
 	var documents []domain.Document
 	for i := 0; i < titleList.Len(); i++ {
-		
+
+		id, _ := idList.GetAsString(i);
 		title, _ := titleList.GetAsString(i);
 		content, _ := contentList.GetAsString(i);
 		category, _ := categoryList.GetAsString(i);
 
 		documents = append(documents, domain.Document{
-			ID: "",
+			ID: id,
 			Title: title,
 			Content: content,
 			Category: category,
+			Metadata: decodeMetadataColumn(metaList, i),
 		})
 	}
-	
-	err = m.Client.ReleaseCollection(
-		context.Background(),                            // ctx
-		collectionName,                                   // CollectionName
+
+	return documents, nil
+}
+
+// decodeMetadataColumn unmarshals row i of the dynamic "$meta" JSON column
+// written by upsertBatch back into a metadata map, returning nil if the
+// column wasn't requested or the row doesn't decode as JSON.
+func decodeMetadataColumn(metaColumn entity.Column, i int) map[string]any {
+	if metaColumn == nil {
+		return nil
+	}
+
+	raw, err := metaColumn.Get(i)
+	if err != nil {
+		return nil
+	}
+
+	bytes, ok := raw.([]byte)
+	if !ok {
+		return nil
+	}
+
+	var metadata map[string]any
+	if err := json.Unmarshal(bytes, &metadata); err != nil {
+		return nil
+	}
+
+	return metadata
+}
+
+// QueryByPks fetches documents by primary key, bypassing ANN search
+// entirely. Useful for metadata-only lookups once a filter expression (see
+// Search) has already narrowed a candidate set of ids.
+func (m *DatastoreMilvusRepository) QueryByPks(collectionName string, ids []string) ([]domain.Document, error) {
+	pks := entity.NewColumnVarChar("id", ids)
+
+	queryResult, err := m.Client.QueryByPks(
+		context.Background(),
+		collectionName,
+		[]string{},
+		pks,
+		[]string{"id", "title", "content", "category", "$meta"},
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to release collection: %w", err)
+		return nil, fmt.Errorf("fail to query by pks: %w", err)
+	}
+
+	idList := queryResult.GetColumn("id")
+	titleList := queryResult.GetColumn("title")
+	contentList := queryResult.GetColumn("content")
+	categoryList := queryResult.GetColumn("category")
+	metaList := queryResult.GetColumn("$meta")
+
+	documents := make([]domain.Document, 0, idList.Len())
+	for i := 0; i < idList.Len(); i++ {
+		id, _ := idList.GetAsString(i)
+		title, _ := titleList.GetAsString(i)
+		content, _ := contentList.GetAsString(i)
+		category, _ := categoryList.GetAsString(i)
+
+		documents = append(documents, domain.Document{
+			ID:       id,
+			Title:    title,
+			Content:  content,
+			Category: category,
+			Metadata: decodeMetadataColumn(metaList, i),
+		})
 	}
 
 	return documents, nil
 }
 
-func (m *DatastoreMilvusRepository) buildIndex(collectionName string) error {
-    idx, err := entity.NewIndexIvfFlat(entity.COSINE, 1024)
-    if err != nil {
-        return fmt.Errorf("fail to create IVF flat index parameter: %w", err)
-    }
+// HybridSearch runs a dense ANN sub-search (COSINE over "embedding") and a
+// sparse ANN sub-search (IP over "sparse_embedding", backed by a
+// SPARSE_INVERTED_INDEX) and fuses the two ranked result sets with
+// reciprocal rank fusion, giving BM25/SPLADE-style lexical recall on top of
+// the existing dense semantic search. opts.DenseParams/opts.SparseParams must
+// match the IndexSpec the "embedding"/"sparse_embedding" fields were built
+// with; pass DefaultHybridSearchOptions() to reproduce the previous
+// hardcoded IVF_FLAT/SPARSE_INVERTED_INDEX behaviour.
+func (m *DatastoreMilvusRepository) HybridSearch(collectionName string, query string, topK int, opts HybridSearchOptions) ([]domain.Document, error) {
+	encodedQuery, err := m.Encoder.Encode(query)
+	if err != nil {
+		return nil, fmt.Errorf("fail to encode query: %w", err)
+	}
 
-    err = m.Client.CreateIndex(context.Background(), collectionName, "embedding", idx, false)
-    if err != nil {
-        return fmt.Errorf("fail to create index: %w", err)
+	encodedSparseQuery, err := m.SparseEncoder.Encode(query)
+	if err != nil {
+		return nil, fmt.Errorf("fail to sparse encode query: %w", err)
+	}
+
+	if err := m.ensureLoaded(collectionName); err != nil {
+		return nil, err
+	}
+
+	if opts.DenseParams == nil {
+		opts.DenseParams = DefaultHybridSearchOptions().DenseParams
+	}
+	if opts.SparseParams == nil {
+		opts.SparseParams = DefaultHybridSearchOptions().SparseParams
+	}
+
+	denseSp, err := opts.DenseParams.BuildSearchParams()
+	if err != nil {
+		return nil, fmt.Errorf("fail to build dense search params: %w", err)
+	}
+
+	sparseSp, err := opts.SparseParams.BuildSearchParams()
+	if err != nil {
+		return nil, fmt.Errorf("fail to build sparse search params: %w", err)
+	}
+
+	opt := client.SearchQueryOptionFunc(func(option *client.SearchQueryOption) {
+		option.Limit = topK
+		option.Offset = 0
+		option.ConsistencyLevel = entity.ClStrong
+		option.IgnoreGrowing = false
+	})
+
+	outputFields := []string{"id", "title", "content", "category"}
+
+	denseResult, err := m.Client.Search(
+		context.Background(),
+		collectionName,
+		[]string{},
+		"",
+		outputFields,
+		[]entity.Vector{entity.FloatVector(encodedQuery)},
+		"embedding",
+		entity.COSINE,
+		topK,
+		denseSp,
+		opt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("fail to run dense sub-search: %w", err)
+	}
+
+	sparseResult, err := m.Client.Search(
+		context.Background(),
+		collectionName,
+		[]string{},
+		"",
+		outputFields,
+		[]entity.Vector{encodedSparseQuery},
+		"sparse_embedding",
+		entity.IP,
+		topK,
+		sparseSp,
+		opt,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("fail to run sparse sub-search: %w", err)
+	}
+
+	return fuseByReciprocalRank(documentsFromSearchResult(denseResult[0]), documentsFromSearchResult(sparseResult[0]), topK), nil
+}
+
+// documentsFromSearchResult decodes the id/title/content/category output
+// columns of a single Milvus sub-search into domain documents, preserving
+// the rank order returned by the server.
+func documentsFromSearchResult(result client.SearchResult) []domain.Document {
+	fields := result.Fields
+	idList := fields.GetColumn("id")
+	titleList := fields.GetColumn("title")
+	contentList := fields.GetColumn("content")
+	categoryList := fields.GetColumn("category")
+
+	documents := make([]domain.Document, 0, idList.Len())
+	for i := 0; i < idList.Len(); i++ {
+		id, _ := idList.GetAsString(i)
+		title, _ := titleList.GetAsString(i)
+		content, _ := contentList.GetAsString(i)
+		category, _ := categoryList.GetAsString(i)
+
+		documents = append(documents, domain.Document{
+			ID:       id,
+			Title:    title,
+			Content:  content,
+			Category: category,
+		})
+	}
+
+	return documents
+}
+
+// fuseByReciprocalRank merges two ranked result sets by id, scoring each
+// occurrence as 1/(rrfK+rank), with rank counted from 1, and summing across
+// lists, then returns the
+// top-k documents ordered by descending fused score.
+func fuseByReciprocalRank(dense []domain.Document, sparse []domain.Document, topK int) []domain.Document {
+	scores := make(map[string]float64)
+	documents := make(map[string]domain.Document)
+
+	accumulate := func(ranked []domain.Document) {
+		for rank, document := range ranked {
+			scores[document.ID] += 1.0 / float64(rrfK+rank+1)
+			if _, seen := documents[document.ID]; !seen {
+				documents[document.ID] = document
+			}
+		}
+	}
+
+	accumulate(dense)
+	accumulate(sparse)
+
+	ids := make([]string, 0, len(documents))
+	for id := range documents {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return scores[ids[i]] > scores[ids[j]]
+	})
+
+	if len(ids) > topK {
+		ids = ids[:topK]
+	}
+
+	fused := make([]domain.Document, 0, len(ids))
+	for _, id := range ids {
+		fused = append(fused, documents[id])
+	}
+
+	return fused
+}
+
+func (m *DatastoreMilvusRepository) buildIndexes(collectionName string, indexSpecs []IndexSpec) error {
+    for _, spec := range indexSpecs {
+        idx, err := spec.BuildIndex()
+        if err != nil {
+            return err
+        }
+
+        if err := m.Client.CreateIndex(context.Background(), collectionName, spec.FieldName(), idx, false); err != nil {
+            return fmt.Errorf("fail to create index on %s: %w", spec.FieldName(), err)
+        }
     }
 
     return nil
@@ -190,9 +591,10 @@ func (m *DatastoreMilvusRepository) buildIndex(collectionName string) error {
 
 func defineSchema(collectionName string) *entity.Schema {
 	return &entity.Schema{
-		CollectionName: collectionName,
-		Description:    "",
-		AutoID:         false,
+		CollectionName:     collectionName,
+		Description:        "",
+		AutoID:             false,
+		EnableDynamicField: true,
 		Fields: []*entity.Field{
 			{
 				Name:       "id",
@@ -237,6 +639,10 @@ func defineSchema(collectionName string) *entity.Schema {
 					entity.TypeParamDim: fmt.Sprintf("%d", 4096),
 				},
 			},
+			{
+				Name:     "sparse_embedding",
+				DataType: entity.FieldTypeSparseFloatVector,
+			},
 		},
 	}
 }
@@ -0,0 +1,125 @@
+package datastore
+
+import (
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// SearchParams builds the Milvus search-time tuning parameters matching
+// whichever IndexSpec a field was indexed with, e.g. nprobe for IVF_FLAT or
+// ef for HNSW.
+type SearchParams interface {
+	BuildSearchParams() (entity.SearchParam, error)
+}
+
+// IVFFlatSearchParams tunes an IVF_FLAT search by the number of clusters probed.
+type IVFFlatSearchParams struct {
+	Nprobe int
+}
+
+func (p IVFFlatSearchParams) BuildSearchParams() (entity.SearchParam, error) {
+	return entity.NewIndexIvfFlatSearchParam(p.Nprobe)
+}
+
+// IVFSQ8SearchParams tunes an IVF_SQ8 search by the number of clusters probed.
+type IVFSQ8SearchParams struct {
+	Nprobe int
+}
+
+func (p IVFSQ8SearchParams) BuildSearchParams() (entity.SearchParam, error) {
+	return entity.NewIndexIvfSQ8SearchParam(p.Nprobe)
+}
+
+// HNSWSearchParams tunes an HNSW search by the size of the dynamic candidate list.
+type HNSWSearchParams struct {
+	Ef int
+}
+
+func (p HNSWSearchParams) BuildSearchParams() (entity.SearchParam, error) {
+	return entity.NewIndexHNSWSearchParam(p.Ef)
+}
+
+// DiskANNSearchParams tunes a DISKANN search by its search list size.
+type DiskANNSearchParams struct {
+	SearchListSize int
+}
+
+func (p DiskANNSearchParams) BuildSearchParams() (entity.SearchParam, error) {
+	return entity.NewIndexDiskANNSearchParam(p.SearchListSize)
+}
+
+// SparseInvertedSearchParams tunes a SPARSE_INVERTED_INDEX search by the
+// fraction of the smallest-weight terms dropped from the query.
+type SparseInvertedSearchParams struct {
+	DropRatio float64
+}
+
+func (p SparseInvertedSearchParams) BuildSearchParams() (entity.SearchParam, error) {
+	return entity.NewIndexSparseInvertedSearchParam(p.DropRatio)
+}
+
+// SearchOptions bundles the per-call tuning Search previously hardcoded.
+type SearchOptions struct {
+	Params           SearchParams
+	TopK             int
+	Offset           int
+	ConsistencyLevel entity.ConsistencyLevel
+}
+
+// DefaultSearchOptions reproduces Search's pre-tuning behaviour: an
+// IVF_FLAT probe of 10 clusters, top 3 results, strong consistency.
+func DefaultSearchOptions() SearchOptions {
+	return SearchOptions{
+		Params:           IVFFlatSearchParams{Nprobe: 10},
+		TopK:             3,
+		Offset:           0,
+		ConsistencyLevel: entity.ClStrong,
+	}
+}
+
+// HybridSearchOptions bundles the per-field search-time tuning HybridSearch
+// needs to match whichever IndexSpec the "embedding" and "sparse_embedding"
+// fields were built with.
+type HybridSearchOptions struct {
+	DenseParams  SearchParams
+	SparseParams SearchParams
+}
+
+// DefaultHybridSearchOptions reproduces HybridSearch's pre-tuning behaviour:
+// an IVF_FLAT probe of 10 clusters for the dense sub-search and a
+// zero-drop-ratio SPARSE_INVERTED_INDEX search for the sparse sub-search.
+func DefaultHybridSearchOptions() HybridSearchOptions {
+	return HybridSearchOptions{
+		DenseParams:  IVFFlatSearchParams{Nprobe: 10},
+		SparseParams: SparseInvertedSearchParams{DropRatio: 0.0},
+	}
+}
+
+// withDefaults fills in the zero-valued fields of o from
+// DefaultSearchOptions, so a partially-constructed SearchOptions (e.g.
+// SearchOptions{TopK: 5}) behaves as the caller intends instead of falling
+// back to a nil search param or a limit-0 search that returns nothing.
+func (o SearchOptions) withDefaults() SearchOptions {
+	defaults := DefaultSearchOptions()
+
+	if o.Params == nil {
+		o.Params = defaults.Params
+	}
+	if o.TopK <= 0 {
+		o.TopK = defaults.TopK
+	}
+	if o.ConsistencyLevel == 0 {
+		o.ConsistencyLevel = defaults.ConsistencyLevel
+	}
+
+	return o
+}
+
+func (o SearchOptions) searchQueryOption() client.SearchQueryOptionFunc {
+	return client.SearchQueryOptionFunc(func(option *client.SearchQueryOption) {
+		option.Limit = o.TopK
+		option.Offset = o.Offset
+		option.ConsistencyLevel = o.ConsistencyLevel
+		option.IgnoreGrowing = false
+	})
+}
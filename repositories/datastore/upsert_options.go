@@ -0,0 +1,76 @@
+package datastore
+
+import (
+	"math"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpsertOptions configures the batching, concurrency and retry behaviour of
+// UpsertDocumentsWithOptions and UpsertDocumentsStream.
+type UpsertOptions struct {
+	// BatchSize is the number of documents encoded and upserted together.
+	BatchSize int
+	// Concurrency is the number of encoder goroutines flushing batches in
+	// parallel; later batches keep encoding while earlier ones are upserting.
+	Concurrency int
+	// OnProgress, if set, is called after each batch is successfully
+	// upserted with the cumulative number of documents written so far.
+	OnProgress func(upserted int)
+	// RetryPolicy controls retries of transient gRPC errors when flushing a batch.
+	RetryPolicy RetryPolicy
+}
+
+// DefaultUpsertOptions returns the options used by UpsertDocuments.
+func DefaultUpsertOptions() UpsertOptions {
+	return UpsertOptions{
+		BatchSize:   128,
+		Concurrency: 4,
+		RetryPolicy: DefaultRetryPolicy(),
+	}
+}
+
+// RetryPolicy configures exponential backoff retries for transient gRPC
+// errors returned while flushing a batch to Milvus.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy returns a conservative backoff suitable for a single
+// query node outage or a momentary gRPC resource exhaustion.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := float64(p.BaseDelay) * math.Pow(2, float64(attempt))
+	if backoff > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+
+	return time.Duration(backoff)
+}
+
+// isTransient reports whether err is a gRPC status worth retrying, as
+// opposed to a permanent failure like an invalid schema or bad argument.
+func isTransient(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch s.Code() {
+	case codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}